@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/metalmatze/i3bargo/modules"
+)
+
+// readClickEvents decodes the click_events stream i3bar writes back on r: a
+// JSON array of events, opened once and then filled in one element at a
+// time for the lifetime of the bar. The returned channel is closed once r
+// is exhausted or yields invalid JSON.
+func readClickEvents(ctx context.Context, r io.Reader) <-chan modules.ClickEvent {
+	events := make(chan modules.ClickEvent)
+
+	go func() {
+		defer close(events)
+
+		dec := json.NewDecoder(r)
+		if _, err := dec.Token(); err != nil { // consume the opening '['
+			return
+		}
+
+		for dec.More() {
+			var event modules.ClickEvent
+			if err := dec.Decode(&event); err != nil {
+				return
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+// dispatchClicks routes each click event to the module it targets, matching
+// on both Block.Name and Block.Instance, if that module implements
+// modules.ClickHandler.
+func dispatchClicks(ctx context.Context, events <-chan modules.ClickEvent, mods []modules.Module, positions map[moduleKey]int, onErr func(name string, err error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			place, ok := positions[moduleKey{Name: event.Name, Instance: event.Instance}]
+			if !ok {
+				continue
+			}
+
+			mod := mods[place]
+			if mod.Name() != event.Name || mod.Instance() != event.Instance {
+				// positions is kept in sync with mods by construction; this
+				// would mean the two have drifted apart.
+				onErr(event.Name, fmt.Errorf("resolved module %s/%s does not match click event %s/%s", mod.Name(), mod.Instance(), event.Name, event.Instance))
+				continue
+			}
+
+			handler, ok := mod.(modules.ClickHandler)
+			if !ok {
+				continue
+			}
+
+			if err := handler.HandleClick(event); err != nil {
+				onErr(event.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/metalmatze/i3bargo/format/human"
+)
+
+// Config is the top-level i3bargo configuration loaded from a TOML file.
+// It lists the enabled modules in display order.
+type Config struct {
+	Modules []ModuleConfig `toml:"module"`
+}
+
+// ModuleConfig configures a single enabled module instance.
+type ModuleConfig struct {
+	// Type selects the module implementation, e.g. "memory", "volume".
+	Type string `toml:"type"`
+	// Interval overrides the module's default poll interval, e.g. "5s".
+	Interval string `toml:"interval"`
+	// Signal, if non-zero, makes the module re-run immediately on
+	// SIGRTMIN+Signal, the same convention i3status uses.
+	Signal int `toml:"signal"`
+	// Instance distinguishes this module from any other of the same Type in
+	// the config, e.g. running both a "wifi" and an "eth" network block.
+	// When unset, main defaults it to the module's position in the list,
+	// which is unique, so repeated Types need not set it.
+	Instance string `toml:"instance"`
+	// Options carries module-specific settings, e.g. the hwmon path for
+	// temperature or the mixer name for volume.
+	Options map[string]string `toml:"options"`
+}
+
+// interval parses Interval, returning zero (and no error) when unset so
+// callers can fall back to the module's own default.
+func (c ModuleConfig) interval() (time.Duration, error) {
+	if c.Interval == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("module %q: invalid interval %q: %w", c.Type, c.Interval, err)
+	}
+	return d, nil
+}
+
+// formatter builds the human.Formatter described by the "unit", "precision"
+// and "compact" options, used by modules that render sizes or rates.
+func (c ModuleConfig) formatter() (human.Formatter, error) {
+	unit, err := human.ParseUnit(c.Options["unit"])
+	if err != nil {
+		return human.Formatter{}, fmt.Errorf("module %q: %w", c.Type, err)
+	}
+
+	precision := -1
+	if p, ok := c.Options["precision"]; ok {
+		precision, err = strconv.Atoi(p)
+		if err != nil {
+			return human.Formatter{}, fmt.Errorf("module %q: invalid precision %q: %w", c.Type, p, err)
+		}
+	}
+
+	compact := c.Options["compact"] == "true"
+
+	return human.NewFormatter(unit, precision, compact), nil
+}
+
+func loadConfig(path string) (Config, error) {
+	var cfg Config
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return Config{}, fmt.Errorf("load config %s: %w", path, err)
+	}
+	return cfg, nil
+}
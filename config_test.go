@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+// TestLoadConfigExample makes sure i3bargo.example.toml actually loads and
+// every module in it builds, so a TOML type mismatch like "precision = 2"
+// (an int, where Options is map[string]string) doesn't ship unnoticed.
+func TestLoadConfigExample(t *testing.T) {
+	cfg, err := loadConfig("i3bargo.example.toml")
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+
+	for i, modCfg := range cfg.Modules {
+		if _, err := newModule(modCfg, "test"); err != nil {
+			t.Errorf("module %d (%s): %v", i, modCfg.Type, err)
+		}
+	}
+}
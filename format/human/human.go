@@ -0,0 +1,130 @@
+// Package human renders sizes, durations, and rates the way a status bar
+// should show them to a person, rather than relying on Go's default
+// formatting (e.g. time.Duration's "75h12m3.2s").
+package human
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Unit selects the base used to scale byte counts: 1024 (IEC, "KiB") or
+// 1000 (SI, "kB").
+type Unit int
+
+const (
+	// IEC scales by 1024 and labels units KiB, MiB, GiB, ...
+	IEC Unit = iota
+	// SI scales by 1000 and labels units kB, MB, GB, ...
+	SI
+)
+
+// ParseUnit parses the "unit" module config option ("iec" or "si"),
+// defaulting to IEC for an empty string.
+func ParseUnit(s string) (Unit, error) {
+	switch s {
+	case "", "iec":
+		return IEC, nil
+	case "si":
+		return SI, nil
+	default:
+		return 0, fmt.Errorf("invalid unit %q (want iec or si)", s)
+	}
+}
+
+var (
+	iecUnits = [...]string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	siUnits  = [...]string{"B", "kB", "MB", "GB", "TB", "PB"}
+)
+
+// Formatter renders byte counts and rates with a consistent unit system,
+// precision, and spacing, configurable per module so users can tune the
+// bar's density without code changes.
+type Formatter struct {
+	Unit Unit
+	// Precision is the number of digits after the decimal point.
+	Precision int
+	// Compact drops the space between the number and its unit, e.g.
+	// "11.4GiB" instead of "11.4 GiB".
+	Compact bool
+}
+
+// NewFormatter builds a Formatter, defaulting precision to 2 when negative.
+func NewFormatter(unit Unit, precision int, compact bool) Formatter {
+	if precision < 0 {
+		precision = 2
+	}
+	return Formatter{Unit: unit, Precision: precision, Compact: compact}
+}
+
+// Bytes renders n scaled to the largest unit that keeps the value >= 1,
+// e.g. Bytes(12298388685) == "11.45 GiB" with IEC and precision 2.
+func (f Formatter) Bytes(n uint64) string {
+	value, unit := f.scale(float64(n))
+	return fmt.Sprintf("%.*f%s%s", f.Precision, value, f.sep(), unit)
+}
+
+// Rate renders bytesPerSec the same way Bytes does, with a "/s" suffix.
+func (f Formatter) Rate(bytesPerSec float64) string {
+	value, unit := f.scale(bytesPerSec)
+	return fmt.Sprintf("%.*f%s%s/s", f.Precision, value, f.sep(), unit)
+}
+
+func (f Formatter) units() [6]string {
+	if f.Unit == SI {
+		return siUnits
+	}
+	return iecUnits
+}
+
+func (f Formatter) base() float64 {
+	if f.Unit == SI {
+		return 1000
+	}
+	return 1024
+}
+
+// scale divides n down by Unit's base until it fits a single digit group,
+// returning the scaled value alongside the unit label it landed on.
+func (f Formatter) scale(n float64) (float64, string) {
+	base := f.base()
+	units := f.units()
+
+	i := 0
+	for n >= base && i < len(units)-1 {
+		n /= base
+		i++
+	}
+	return n, units[i]
+}
+
+func (f Formatter) sep() string {
+	if f.Compact {
+		return ""
+	}
+	return " "
+}
+
+// Duration renders d the way a bar should show an uptime or a remaining
+// time, e.g. "3d 4h 12m" instead of Go's "76h12m0s".
+func Duration(d time.Duration) string {
+	d = d.Round(time.Minute)
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if days > 0 || hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	parts = append(parts, fmt.Sprintf("%dm", minutes))
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,64 @@
+package human
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatterBytes(t *testing.T) {
+	cases := []struct {
+		name string
+		f    Formatter
+		n    uint64
+		want string
+	}{
+		{"iec default precision", NewFormatter(IEC, -1, false), 12298388685, "11.45 GiB"},
+		{"si", NewFormatter(SI, -1, false), 12300000000, "12.30 GB"},
+		{"iec rollover boundary", NewFormatter(IEC, 0, false), 1024, "1 KiB"},
+		{"below rollover stays in base unit", NewFormatter(IEC, 0, false), 1023, "1023 B"},
+		{"top unit has nowhere left to roll over to", NewFormatter(IEC, 0, false), 1 << 60, "1024 PiB"},
+		{"precision 0", NewFormatter(IEC, 0, false), 3 * 1024, "3 KiB"},
+		{"compact drops the space", NewFormatter(IEC, 1, true), 1536, "1.5KiB"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.f.Bytes(c.n); got != c.want {
+				t.Errorf("Bytes(%d) = %q, want %q", c.n, got, c.want)
+			}
+		})
+	}
+}
+
+func TestFormatterRate(t *testing.T) {
+	f := NewFormatter(IEC, 1, true)
+	const mib = 1024 * 1024
+	if got, want := f.Rate(1.2*mib), "1.2MiB/s"; got != want {
+		t.Errorf("Rate(1.2MiB) = %q, want %q", got, want)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	cases := []struct {
+		name string
+		d    string
+		want string
+	}{
+		{"days hours minutes", "76h12m", "3d 4h 12m"},
+		{"hours only, no days", "2h5m", "2h 5m"},
+		{"minutes only, rounds up", "90s", "2m"},
+		{"zero", "0s", "0m"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d, err := time.ParseDuration(c.d)
+			if err != nil {
+				t.Fatalf("ParseDuration(%q): %v", c.d, err)
+			}
+			if got := Duration(d); got != c.want {
+				t.Errorf("Duration(%s) = %q, want %q", c.d, got, c.want)
+			}
+		})
+	}
+}
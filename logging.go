@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// newLogger builds the slog.Logger i3bargo logs through, per the
+// -log-level/-log-file/-log-format flags. It returns the file backing the
+// logger (if any) so the caller can close it.
+//
+// Setting I3BARGO_DEBUG=1 forces debug level regardless of -log-level; this
+// is also what main checks to decide whether to dump every produced block.
+func newLogger(level, path, format string) (*slog.Logger, io.Closer, error) {
+	var w io.Writer = os.Stderr
+	var closer io.Closer = nopCloser{}
+
+	if path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open log file %s: %w", path, err)
+		}
+		w, closer = f, f
+	}
+
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, nil, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+	if debugEnabled() {
+		lvl = slog.LevelDebug
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler
+	switch format {
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	case "text":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, nil, fmt.Errorf("invalid log format %q (want json or text)", format)
+	}
+
+	return slog.New(handler), closer, nil
+}
+
+// debugEnabled reports whether I3BARGO_DEBUG=1 is set, enabling debug-level
+// logging and a dump of every block produced regardless of -log-level.
+func debugEnabled() bool {
+	return os.Getenv("I3BARGO_DEBUG") == "1"
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
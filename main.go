@@ -1,348 +1,174 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
-	"io/ioutil"
+	"log/slog"
 	"os"
-	"os/exec"
-	"regexp"
+	"os/signal"
 	"strconv"
-	"time"
+	"syscall"
 
-	batt "github.com/distatus/battery"
-	"github.com/metalmatze/i3bargo/fontawesome"
+	"github.com/metalmatze/i3bargo/modules"
 )
 
-// Block is a container for the information that being displayed.
-type Block struct {
-	FullText            string `json:"full_text"`
-	ShortText           string `json:"short_text,omitempty"`
-	Color               string `json:"color,omitempty"`
-	Background          string `json:"background,omitempty"`
-	Border              string `json:"border,omitempty"`
-	MinWidth            int    `json:"min_width,omitempty"`
-	Align               string `json:"align,omitempty"`
-	Urgent              bool   `json:"urgent,omitempty"`
-	Name                string `json:"name,omitempty"`
-	Instance            string `json:"instance,omitempty"`
-	Separator           bool   `json:"separator,omitempty"`
-	SeparatorBlockWidth int    `json:"separator_block_width,omitempty"`
+// sigrtmin is Linux's SIGRTMIN, the base for the "SIGRTMIN+n" convention
+// i3status uses for its per-module "signal" option.
+const sigrtmin = 34
+
+// moduleKey identifies a configured module instance, matching the Name and
+// Instance an Update or ClickEvent carries. Name alone isn't unique: config
+// can run several instances of the same Type (two network interfaces, two
+// temperature sensors), and they all share the same Name.
+type moduleKey struct {
+	Name     string
+	Instance string
 }
 
-// Update is an event send by funcs to update the state.
-type Update struct {
-	Place   uint
-	Content json.RawMessage
-	Error   error
-}
-
-type updater func(place uint, updates chan<- Update)
-
 func main() {
-	logs, err := ioutil.TempFile("", "i3bargo")
+	configPath := flag.String("config", "/etc/i3bargo.toml", "path to the i3bargo TOML config")
+	logLevel := flag.String("log-level", "info", "log level: debug, info, warn, or error")
+	logFile := flag.String("log-file", "", "path to write logs to (default stderr)")
+	logFormat := flag.String("log-format", "text", "log format: text or json")
+	flag.Parse()
+
+	logger, closer, err := newLogger(*logLevel, *logFile, *logFormat)
 	if err != nil {
-		fmt.Println("failed to open logs file:", err)
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
-	defer logs.Close()
+	defer closer.Close()
 
-	updates := make(chan Update)
-
-	updaters := []updater{
-		memoryUpdater,
-		volumeUpdater,
-		temperatureUpdater,
-		batteryUpdater,
-		uptimeUpdater,
-		datetimeUpdater,
-	}
-
-	for i, updater := range updaters {
-		go updater(uint(i), updates)
-	}
-
-	state := make([]json.RawMessage, len(updaters))
-
-	fmt.Println(`{ "version": 1 }`)
-	fmt.Println("[")
-	for update := range updates {
-		state[update.Place] = update.Content
-
-		if update.Error != nil {
-			logs.WriteString(fmt.Sprintf("error in updater: %v\n", update.Error))
-			logs.Sync()
-			state[update.Place] = json.RawMessage(`{"full_text":" error","separator":true,"separator_block_width":20}`)
-		}
-
-		fmt.Println("[")
-		for i, s := range state {
-			if len(s) == 0 {
-				s = []byte(`{"full_text":""}`)
-			}
-
-			comma := ""
-			if i < len(state)-1 {
-				comma = ","
-			}
-
-			fmt.Printf("\t%s%s\n", s, comma)
-		}
-		fmt.Println("],")
+	if err := run(*configPath, logger); err != nil {
+		logger.Error("fatal", "error", err)
+		os.Exit(1)
 	}
 }
 
-func batteryUpdater(place uint, updates chan<- Update) {
+// forwardWakeups relays each signal received on sig to wake, dropping it
+// instead of blocking if the module hasn't consumed the previous wake-up yet.
+func forwardWakeups(ctx context.Context, sig <-chan os.Signal, wake chan<- struct{}) {
 	for {
-		out, err := battery()
-
-		updates <- Update{
-			Place:   place,
-			Content: out,
-			Error:   err,
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+			select {
+			case wake <- struct{}{}:
+			default:
+			}
 		}
-
-		time.Sleep(time.Second)
 	}
 }
 
-func battery() (json.RawMessage, error) {
-	b, err := batt.Get(0)
+func run(configPath string, logger *slog.Logger) error {
+	cfg, err := loadConfig(configPath)
 	if err != nil {
-		return nil, err // TODO: Use errors.Wrap
+		return err
 	}
 
-	w := &bytes.Buffer{}
-
-	w.WriteString(fmt.Sprintf("%s ", fontawesome.BatteryFull))
+	mods := make([]modules.Module, 0, len(cfg.Modules))
+	wakes := make([]chan struct{}, 0, len(cfg.Modules))
+	positions := make(map[moduleKey]int, len(cfg.Modules))
 
-	fmt.Fprintf(w, "%.0f%%", (b.Current/b.Full)*100)
+	for _, modCfg := range cfg.Modules {
+		place := len(mods)
 
-	if b.Current != b.Full {
-		d, err := time.ParseDuration(fmt.Sprintf("%fh", b.Current/b.ChargeRate))
-		if err != nil {
-			return nil, err // TODO: Use errors.Wrap
+		instance := modCfg.Instance
+		if instance == "" {
+			// Default to the module's position, which is always unique,
+			// so two instances of the same Type don't collide below.
+			instance = strconv.Itoa(place)
 		}
 
-		w.WriteString(" - ")
-
-		if d.Hours() > 1 {
-			fmt.Fprintf(w, "%dh", int(d.Hours()))
-		} else {
-			fmt.Fprintf(w, "%dm", int(d.Minutes()))
-		}
-	}
-
-	block := Block{
-		FullText:            w.String(),
-		Separator:           true,
-		SeparatorBlockWidth: 20,
-	}
-
-	return json.Marshal(block)
-}
-
-func datetimeUpdater(place uint, updates chan<- Update) {
-	for {
-		out, err := datetime()
-
-		updates <- Update{
-			Place:   place,
-			Content: out,
-			Error:   err,
-		}
-
-		time.Sleep(time.Second)
-	}
-}
-
-func datetime() (json.RawMessage, error) {
-	b := Block{
-		FullText:            time.Now().Format("2006-01-02 15:04:05"),
-		Separator:           true,
-		SeparatorBlockWidth: 20,
-	}
-
-	return json.Marshal(b)
-}
-
-func uptimeUpdater(place uint, updates chan<- Update) {
-	for {
-		out, err := uptime()
-
-		updates <- Update{
-			Place:   place,
-			Content: out,
-			Error:   err,
-		}
-
-		time.Sleep(10 * time.Second)
-	}
-}
-
-func uptime() (json.RawMessage, error) {
-	content, err := ioutil.ReadFile("/proc/uptime")
-	if err != nil {
-		return nil, err // TODO: Use errors.Wrap
-	}
-	content = bytes.TrimSpace(content)
-	contents := bytes.Split(content, []byte(" "))
-
-	uptimeFloat, err := strconv.ParseFloat(string(contents[0]), 64)
-	if err != nil {
-		return nil, err // TODO: Use errors.Wrap
-	}
-
-	uptime := time.Duration(uptimeFloat) * time.Second
-
-	b := Block{
-		FullText:            fmt.Sprintf("%s %s", fontawesome.ArrowCircleUp, uptime.String()),
-		Separator:           true,
-		SeparatorBlockWidth: 20,
-	}
-
-	return json.Marshal(b)
-}
-
-func temperatureUpdater(place uint, updates chan<- Update) {
-	for {
-		out, err := temperature()
-
-		updates <- Update{
-			Place:   place,
-			Content: out,
-			Error:   err,
+		mod, err := newModule(modCfg, instance)
+		if err != nil {
+			return fmt.Errorf("configure module %d: %w", place, err)
 		}
-
-		time.Sleep(5 * time.Second)
-	}
-}
-
-func temperature() (json.RawMessage, error) {
-	content, err := ioutil.ReadFile("/sys/class/hwmon/hwmon1/temp1_input")
-	if err != nil {
-		return nil, err
-	}
-	content = bytes.TrimSpace(content)
-
-	celsius, err := strconv.ParseInt(string(content), 10, 64)
-	if err != nil {
-		return nil, err
-	}
-
-	b := Block{
-		FullText:            fmt.Sprintf("%s %d°C", fontawesome.ThermometerFull, celsius/1000),
-		Separator:           true,
-		SeparatorBlockWidth: 20,
-	}
-
-	return json.Marshal(b)
-}
-
-func volumeUpdater(place uint, updates chan<- Update) {
-	for {
-		out, err := volume()
-
-		updates <- Update{
-			Place:   place,
-			Content: out,
-			Error:   err,
+		key := moduleKey{Name: mod.Name(), Instance: mod.Instance()}
+		if _, exists := positions[key]; exists {
+			return fmt.Errorf("configure module %d: duplicate %s instance %q, set a unique instance", place, mod.Name(), mod.Instance())
 		}
-
-		time.Sleep(time.Second)
+		positions[key] = place
+		mods = append(mods, mod)
+		wakes = append(wakes, make(chan struct{}, 1))
+		logger.Info("module configured", "module", mod.Name(), "instance", mod.Instance(), "place", place)
 	}
-}
-
-var volumeRegex = regexp.MustCompile(`\[(\d{1,3})\%\]\s\[(on|off)\]`)
 
-func volume() (json.RawMessage, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "amixer", "-D", "default", "get", "Master")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, err // TODO: Use errors.Wrap
-	}
-
-	var volText, muteText string
+	term := make(chan os.Signal, 1)
+	signal.Notify(term, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-term
+		cancel()
+	}()
 
-	scanner := bufio.NewScanner(bytes.NewBuffer(output))
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if volumeRegex.MatchString(line) {
-			matches := volumeRegex.FindStringSubmatch(line)
-			volText, muteText = matches[1], matches[2]
-			break
+	for i, modCfg := range cfg.Modules {
+		if modCfg.Signal == 0 {
+			continue
 		}
+		wakeSig := make(chan os.Signal, 1)
+		signal.Notify(wakeSig, syscall.Signal(sigrtmin+modCfg.Signal))
+		go forwardWakeups(ctx, wakeSig, wakes[i])
+	}
+
+	updates := make(chan modules.Update)
+	for i, mod := range mods {
+		place := i
+		go func(mod modules.Module, wake <-chan struct{}) {
+			logger.Debug("module starting", "module", mod.Name(), "place", place)
+			if err := mod.Run(ctx, wake, updates); err != nil && ctx.Err() == nil {
+				logger.Error("module stopped", "module", mod.Name(), "place", place, "error", err)
+			}
+		}(mod, wakes[i])
 	}
 
-	vol, err := strconv.ParseInt(volText, 10, 64)
-	if err != nil {
-		return nil, err // TODO: Use errors.Wrap
-
-	}
-
-	muted := false
-	if muteText == "off" {
-		muted = true
-	}
-
-	fulltext := fmt.Sprintf("%d%%", vol)
-	if muted {
-		fulltext = "off"
-	}
-
-	b := Block{
-		FullText:            fmt.Sprintf("%s %s", fontawesome.VolumeUp, fulltext),
-		Separator:           true,
-		SeparatorBlockWidth: 20,
-	}
+	clicks := readClickEvents(ctx, os.Stdin)
+	go dispatchClicks(ctx, clicks, mods, positions, func(name string, err error) {
+		logger.Error("click handler failed", "module", name, "error", err)
+	})
 
-	return json.Marshal(b)
-}
+	state := make([]json.RawMessage, len(mods))
 
-func memoryUpdater(place uint, updates chan<- Update) {
+	fmt.Println(`{ "version": 1, "click_events": true }`)
+	fmt.Println("[")
 	for {
-		out, err := memory()
-
-		updates <- Update{
-			Place:   place,
-			Content: out,
-			Error:   err,
-		}
+		select {
+		case <-ctx.Done():
+			fmt.Println("]")
+			return nil
+		case update := <-updates:
+			place, ok := positions[moduleKey{Name: update.Name, Instance: update.Instance}]
+			if !ok {
+				continue
+			}
+			state[place] = update.Content
 
-		time.Sleep(time.Second)
-	}
-}
+			if update.Error != nil {
+				logger.Error("module update failed", "module", update.Name, "instance", update.Instance, "place", place, "error", update.Error)
+				state[place] = json.RawMessage(`{"full_text":" error","separator":true,"separator_block_width":20}`)
+			} else if debugEnabled() {
+				logger.Debug("module update", "module", update.Name, "place", place, "block", string(update.Content))
+			}
 
-func memory() (json.RawMessage, error) {
-	file, err := os.Open("/proc/meminfo")
-	if err != nil {
-		return nil, err // TODO: Use errors.Wrap
-	}
-	defer file.Close()
+			fmt.Println("[")
+			for i, s := range state {
+				if len(s) == 0 {
+					s = []byte(`{"full_text":""}`)
+				}
 
-	var total, free, available float64
-	_, err = fmt.Fscanf(file,
-		"MemTotal: %f kB\nMemFree: %f kB\nMemAvailable: %f",
-		&total,
-		&free,
-		&available,
-	)
-	if err != nil {
-		return nil, err // TODO: Use errors.Wrap
-	}
+				comma := ""
+				if i < len(state)-1 {
+					comma = ","
+				}
 
-	b := Block{
-		FullText:            fmt.Sprintf("%s %.2fG", fontawesome.Microchip, available/(1024*1024)),
-		Separator:           true,
-		SeparatorBlockWidth: 20,
+				fmt.Printf("\t%s%s\n", s, comma)
+			}
+			fmt.Println("],")
+		}
 	}
-
-	return json.Marshal(b)
 }
@@ -0,0 +1,101 @@
+// Package battery implements the modules.Module that reports charge and
+// time remaining for the system battery.
+package battery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"time"
+
+	batt "github.com/distatus/battery"
+	"github.com/metalmatze/i3bargo/fontawesome"
+	"github.com/metalmatze/i3bargo/modules"
+)
+
+// Module reports the battery identified by Index on a fixed interval.
+type Module struct {
+	Interval time.Duration
+	// Index selects which battery to report when several are present.
+	Index int
+
+	instance string
+}
+
+// New builds a Module, defaulting Interval to one second. instance
+// distinguishes this Module from any other "battery" instance in the
+// config and is used to place Updates and route click events.
+func New(interval time.Duration, index int, instance string) *Module {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Module{Interval: interval, Index: index, instance: instance}
+}
+
+// Name implements modules.Module.
+func (m *Module) Name() string { return "battery" }
+
+// Instance implements modules.Module.
+func (m *Module) Instance() string { return m.instance }
+
+// Run implements modules.Module.
+func (m *Module) Run(ctx context.Context, wake <-chan struct{}, out chan<- modules.Update) error {
+	return modules.RunLoop(ctx, wake, out, m.Name(), m.Instance(), m.Interval, nil, m.read)
+}
+
+// HandleClick implements modules.ClickHandler: any click shows the output
+// of `upower -d` in a desktop notification.
+func (m *Module) HandleClick(modules.ClickEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "upower", "-d").Output()
+	if err != nil {
+		return fmt.Errorf("upower -d: %w", err)
+	}
+
+	if err := exec.CommandContext(ctx, "notify-send", "Battery", string(out)).Run(); err != nil {
+		return fmt.Errorf("notify-send: %w", err)
+	}
+	return nil
+}
+
+func (m *Module) read() (json.RawMessage, error) {
+	b, err := batt.Get(m.Index)
+	if err != nil {
+		return nil, fmt.Errorf("get battery: %w", err)
+	}
+
+	w := &bytes.Buffer{}
+
+	w.WriteString(fmt.Sprintf("%s ", fontawesome.BatteryFull))
+
+	fmt.Fprintf(w, "%.0f%%", (b.Current/b.Full)*100)
+
+	if b.Current != b.Full {
+		d, err := time.ParseDuration(fmt.Sprintf("%fh", b.Current/b.ChargeRate))
+		if err != nil {
+			return nil, fmt.Errorf("parse charge duration: %w", err)
+		}
+
+		w.WriteString(" - ")
+
+		if d.Hours() > 1 {
+			fmt.Fprintf(w, "%dh", int(d.Hours()))
+		} else {
+			fmt.Fprintf(w, "%dm", int(d.Minutes()))
+		}
+	}
+
+	block := modules.Block{
+		FullText:            w.String(),
+		Name:                "battery",
+		Instance:            m.instance,
+		Separator:           true,
+		SeparatorBlockWidth: 20,
+	}
+
+	return json.Marshal(block)
+}
@@ -0,0 +1,80 @@
+// Package datetime implements the modules.Module that reports the current
+// date and time.
+package datetime
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/metalmatze/i3bargo/modules"
+)
+
+// Module reports the current date and time on a fixed interval.
+type Module struct {
+	Interval time.Duration
+	// Format is the default time.Format layout string.
+	Format string
+	// LongFormat is used instead of Format while the block has been
+	// clicked, toggling back on the next click.
+	LongFormat string
+
+	long int32 // atomic bool, flipped by HandleClick
+
+	instance string
+}
+
+// New builds a Module, defaulting Interval to one second, Format to
+// "2006-01-02 15:04:05" and LongFormat to "Monday, 02 January 2006 15:04:05".
+// instance distinguishes this Module from any other "datetime" instance in
+// the config and is used to place Updates and route click events.
+func New(interval time.Duration, format, instance string) *Module {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if format == "" {
+		format = "2006-01-02 15:04:05"
+	}
+	return &Module{
+		Interval:   interval,
+		Format:     format,
+		LongFormat: "Monday, 02 January 2006 15:04:05",
+		instance:   instance,
+	}
+}
+
+// Name implements modules.Module.
+func (m *Module) Name() string { return "datetime" }
+
+// Instance implements modules.Module.
+func (m *Module) Instance() string { return m.instance }
+
+// Run implements modules.Module.
+func (m *Module) Run(ctx context.Context, wake <-chan struct{}, out chan<- modules.Update) error {
+	return modules.RunLoop(ctx, wake, out, m.Name(), m.Instance(), m.Interval, nil, m.read)
+}
+
+// HandleClick implements modules.ClickHandler: any click toggles between
+// Format and LongFormat.
+func (m *Module) HandleClick(modules.ClickEvent) error {
+	atomic.AddInt32(&m.long, 1)
+	return nil
+}
+
+func (m *Module) read() (json.RawMessage, error) {
+	format := m.Format
+	if atomic.LoadInt32(&m.long)%2 == 1 {
+		format = m.LongFormat
+	}
+
+	b := modules.Block{
+		FullText:            time.Now().Format(format),
+		Name:                "datetime",
+		Instance:            m.instance,
+		Separator:           true,
+		SeparatorBlockWidth: 20,
+	}
+
+	return json.Marshal(b)
+}
@@ -0,0 +1,73 @@
+// Package memory implements the modules.Module that reports available
+// system memory read from /proc/meminfo.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/metalmatze/i3bargo/fontawesome"
+	"github.com/metalmatze/i3bargo/format/human"
+	"github.com/metalmatze/i3bargo/modules"
+)
+
+// Module reports available memory on a fixed interval.
+type Module struct {
+	Interval  time.Duration
+	Formatter human.Formatter
+
+	instance string
+}
+
+// New builds a Module, defaulting Interval to one second. instance
+// distinguishes this Module from any other "memory" instance in the config
+// and is used to place Updates and route click events.
+func New(interval time.Duration, formatter human.Formatter, instance string) *Module {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Module{Interval: interval, Formatter: formatter, instance: instance}
+}
+
+// Name implements modules.Module.
+func (m *Module) Name() string { return "memory" }
+
+// Instance implements modules.Module.
+func (m *Module) Instance() string { return m.instance }
+
+// Run implements modules.Module.
+func (m *Module) Run(ctx context.Context, wake <-chan struct{}, out chan<- modules.Update) error {
+	return modules.RunLoop(ctx, wake, out, m.Name(), m.Instance(), m.Interval, nil, m.read)
+}
+
+func (m *Module) read() (json.RawMessage, error) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return nil, fmt.Errorf("open meminfo: %w", err)
+	}
+	defer file.Close()
+
+	var total, free, available float64
+	_, err = fmt.Fscanf(file,
+		"MemTotal: %f kB\nMemFree: %f kB\nMemAvailable: %f",
+		&total,
+		&free,
+		&available,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("parse meminfo: %w", err)
+	}
+
+	b := modules.Block{
+		FullText:            fmt.Sprintf("%s %s", fontawesome.Microchip, m.Formatter.Bytes(uint64(available*1024))),
+		Name:                "memory",
+		Instance:            m.instance,
+		Separator:           true,
+		SeparatorBlockWidth: 20,
+	}
+
+	return json.Marshal(b)
+}
@@ -0,0 +1,68 @@
+// Package modules defines the common interface status sources implement so
+// that main can assemble a bar from a config file instead of a hard-coded
+// list of updater funcs.
+package modules
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Block mirrors a single block of the i3bar protocol.
+type Block struct {
+	FullText            string `json:"full_text"`
+	ShortText           string `json:"short_text,omitempty"`
+	Color               string `json:"color,omitempty"`
+	Background          string `json:"background,omitempty"`
+	Border              string `json:"border,omitempty"`
+	MinWidth            int    `json:"min_width,omitempty"`
+	Align               string `json:"align,omitempty"`
+	Urgent              bool   `json:"urgent,omitempty"`
+	Name                string `json:"name,omitempty"`
+	Instance            string `json:"instance,omitempty"`
+	Separator           bool   `json:"separator,omitempty"`
+	SeparatorBlockWidth int    `json:"separator_block_width,omitempty"`
+}
+
+// Update is an event a Module sends to report its latest Block.
+type Update struct {
+	Name     string
+	Instance string
+	Content  json.RawMessage
+	Error    error
+}
+
+// ClickEvent is a single click i3bar reports back to us on stdin.
+type ClickEvent struct {
+	Name      string   `json:"name"`
+	Instance  string   `json:"instance"`
+	Button    int      `json:"button"`
+	X         int      `json:"x"`
+	Y         int      `json:"y"`
+	Modifiers []string `json:"modifiers"`
+}
+
+// Module is a single status source that can be assembled into the bar.
+type Module interface {
+	// Name identifies the module's type, e.g. "network" or "temperature".
+	// It is used as the Block's "name" field.
+	Name() string
+	// Instance identifies this particular instance of Name, e.g. which
+	// network interface or which hwmon path. Together with Name it forms
+	// the unique identity main uses to place Updates and route click
+	// events back to the module that produced them — two instances of the
+	// same Name must have distinct Instances.
+	Instance() string
+	// Run produces Updates on out until ctx is cancelled. wake is signalled
+	// to ask the module to re-run immediately, ahead of its own interval,
+	// e.g. in response to a SIGRTMIN+n wake-up signal or an external event
+	// feed; modules that have nothing to react to simply select on it
+	// alongside their ticker.
+	Run(ctx context.Context, wake <-chan struct{}, out chan<- Update) error
+}
+
+// ClickHandler is implemented by modules that react to i3bar click events.
+// Modules that don't care about clicks simply don't implement it.
+type ClickHandler interface {
+	HandleClick(ClickEvent) error
+}
@@ -0,0 +1,129 @@
+// Package network implements the modules.Module that reports send/receive
+// throughput for a network interface read from /proc/net/dev.
+package network
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metalmatze/i3bargo/fontawesome"
+	"github.com/metalmatze/i3bargo/format/human"
+	"github.com/metalmatze/i3bargo/modules"
+)
+
+// Module reports RX/TX throughput for Iface on a fixed interval, derived
+// from the delta between consecutive /proc/net/dev byte counters.
+type Module struct {
+	Interval  time.Duration
+	Iface     string
+	Formatter human.Formatter
+
+	mu          sync.Mutex
+	lastRX      uint64
+	lastTX      uint64
+	lastSampled time.Time
+
+	instance string
+}
+
+// New builds a Module, defaulting Interval to one second and Iface to eth0.
+// instance distinguishes this Module from any other "network" instance in
+// the config (e.g. a second interface) and is used to place Updates and
+// route click events.
+func New(interval time.Duration, iface string, formatter human.Formatter, instance string) *Module {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if iface == "" {
+		iface = "eth0"
+	}
+	return &Module{Interval: interval, Iface: iface, Formatter: formatter, instance: instance}
+}
+
+// Name implements modules.Module.
+func (m *Module) Name() string { return "network" }
+
+// Instance implements modules.Module.
+func (m *Module) Instance() string { return m.instance }
+
+// Run implements modules.Module.
+func (m *Module) Run(ctx context.Context, wake <-chan struct{}, out chan<- modules.Update) error {
+	return modules.RunLoop(ctx, wake, out, m.Name(), m.Instance(), m.Interval, nil, m.read)
+}
+
+func (m *Module) read() (json.RawMessage, error) {
+	rx, tx, err := readCounters(m.Iface)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	m.mu.Lock()
+	var rxRate, txRate float64
+	if !m.lastSampled.IsZero() {
+		elapsed := now.Sub(m.lastSampled).Seconds()
+		// rx/tx can go backwards if the interface bounces or its counters
+		// wrap, in which case there's no sane delta: treat it as 0 instead
+		// of underflowing the uint64 subtraction into a huge rate.
+		if elapsed > 0 && rx >= m.lastRX && tx >= m.lastTX {
+			rxRate = float64(rx-m.lastRX) / elapsed
+			txRate = float64(tx-m.lastTX) / elapsed
+		}
+	}
+	m.lastRX, m.lastTX, m.lastSampled = rx, tx, now
+	m.mu.Unlock()
+
+	b := modules.Block{
+		FullText:            fmt.Sprintf("%s ↓%s ↑%s", fontawesome.ExchangeAlt, m.Formatter.Rate(rxRate), m.Formatter.Rate(txRate)),
+		Name:                "network",
+		Instance:            m.instance,
+		Separator:           true,
+		SeparatorBlockWidth: 20,
+	}
+
+	return json.Marshal(b)
+}
+
+// readCounters returns the cumulative received and transmitted byte
+// counters for iface from /proc/net/dev.
+func readCounters(iface string) (rx, tx uint64, err error) {
+	file, err := os.Open("/proc/net/dev")
+	if err != nil {
+		return 0, 0, fmt.Errorf("open /proc/net/dev: %w", err)
+	}
+	defer file.Close()
+
+	prefix := iface + ":"
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+
+		fields := strings.Fields(strings.TrimPrefix(line, prefix))
+		if len(fields) < 9 {
+			return 0, 0, fmt.Errorf("parse /proc/net/dev: unexpected field count for %s", iface)
+		}
+
+		rx, err = strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("parse rx bytes: %w", err)
+		}
+		tx, err = strconv.ParseUint(fields[8], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("parse tx bytes: %w", err)
+		}
+		return rx, tx, nil
+	}
+
+	return 0, 0, fmt.Errorf("interface %s not found in /proc/net/dev", iface)
+}
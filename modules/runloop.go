@@ -0,0 +1,58 @@
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// maxBackoff caps how long RunLoop will wait between retries after a run
+// of failures from read.
+const maxBackoff = 5 * time.Minute
+
+// RunLoop is the shared poll loop used by modules: it calls read, emits the
+// resulting Update on out, then waits for interval, wake, extraWake, or ctx
+// cancellation before looping. extraWake lets a module fold in an external
+// event feed (e.g. volume's alsactl monitor) alongside its own interval and
+// wake; pass nil if the module has none. If extraWake is closed, RunLoop
+// stops selecting on it and falls back to interval/wake alone. On repeated
+// errors from read it backs off exponentially (doubling interval up to
+// maxBackoff) instead of hammering a failing source every interval.
+func RunLoop(ctx context.Context, wake <-chan struct{}, out chan<- Update, name, instance string, interval time.Duration, extraWake <-chan struct{}, read func() (json.RawMessage, error)) error {
+	delay := interval
+	failures := 0
+
+	for {
+		content, err := read()
+		out <- Update{Name: name, Instance: instance, Content: content, Error: err}
+
+		if err != nil {
+			failures++
+			delay = interval
+			for i := 0; i < failures && delay < maxBackoff; i++ {
+				delay *= 2
+			}
+			if delay > maxBackoff {
+				delay = maxBackoff
+			}
+		} else {
+			failures = 0
+			delay = interval
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		case <-wake:
+			timer.Stop()
+		case _, ok := <-extraWake:
+			timer.Stop()
+			if !ok {
+				extraWake = nil
+			}
+		}
+	}
+}
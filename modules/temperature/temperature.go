@@ -0,0 +1,73 @@
+// Package temperature implements the modules.Module that reports a hwmon
+// sensor reading in degrees Celsius.
+package temperature
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"github.com/metalmatze/i3bargo/fontawesome"
+	"github.com/metalmatze/i3bargo/modules"
+)
+
+// Module reports a hwmon temperature sensor on a fixed interval.
+type Module struct {
+	Interval time.Duration
+	// Path is the hwmon input file to read, e.g. /sys/class/hwmon/hwmon1/temp1_input.
+	Path string
+
+	instance string
+}
+
+// New builds a Module, defaulting Interval to five seconds. instance
+// distinguishes this Module from any other "temperature" instance in the
+// config (e.g. a second hwmon sensor) and is used to place Updates and
+// route click events.
+func New(interval time.Duration, path, instance string) *Module {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	if path == "" {
+		path = "/sys/class/hwmon/hwmon1/temp1_input"
+	}
+	return &Module{Interval: interval, Path: path, instance: instance}
+}
+
+// Name implements modules.Module.
+func (m *Module) Name() string { return "temperature" }
+
+// Instance implements modules.Module.
+func (m *Module) Instance() string { return m.instance }
+
+// Run implements modules.Module.
+func (m *Module) Run(ctx context.Context, wake <-chan struct{}, out chan<- modules.Update) error {
+	return modules.RunLoop(ctx, wake, out, m.Name(), m.Instance(), m.Interval, nil, m.read)
+}
+
+func (m *Module) read() (json.RawMessage, error) {
+	content, err := ioutil.ReadFile(m.Path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", m.Path, err)
+	}
+	content = bytes.TrimSpace(content)
+
+	celsius, err := strconv.ParseInt(string(content), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse temperature: %w", err)
+	}
+
+	b := modules.Block{
+		FullText:            fmt.Sprintf("%s %d°C", fontawesome.ThermometerFull, celsius/1000),
+		Name:                "temperature",
+		Instance:            m.instance,
+		Separator:           true,
+		SeparatorBlockWidth: 20,
+	}
+
+	return json.Marshal(b)
+}
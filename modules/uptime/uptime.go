@@ -0,0 +1,71 @@
+// Package uptime implements the modules.Module that reports system uptime
+// read from /proc/uptime.
+package uptime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"github.com/metalmatze/i3bargo/fontawesome"
+	"github.com/metalmatze/i3bargo/format/human"
+	"github.com/metalmatze/i3bargo/modules"
+)
+
+// Module reports system uptime on a fixed interval.
+type Module struct {
+	Interval time.Duration
+
+	instance string
+}
+
+// New builds a Module, defaulting Interval to ten seconds. instance
+// distinguishes this Module from any other "uptime" instance in the config
+// and is used to place Updates and route click events.
+func New(interval time.Duration, instance string) *Module {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	return &Module{Interval: interval, instance: instance}
+}
+
+// Name implements modules.Module.
+func (m *Module) Name() string { return "uptime" }
+
+// Instance implements modules.Module.
+func (m *Module) Instance() string { return m.instance }
+
+// Run implements modules.Module.
+func (m *Module) Run(ctx context.Context, wake <-chan struct{}, out chan<- modules.Update) error {
+	return modules.RunLoop(ctx, wake, out, m.Name(), m.Instance(), m.Interval, nil, m.read)
+}
+
+func (m *Module) read() (json.RawMessage, error) {
+	content, err := ioutil.ReadFile("/proc/uptime")
+	if err != nil {
+		return nil, fmt.Errorf("read uptime: %w", err)
+	}
+	content = bytes.TrimSpace(content)
+	contents := bytes.Split(content, []byte(" "))
+
+	uptimeFloat, err := strconv.ParseFloat(string(contents[0]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse uptime: %w", err)
+	}
+
+	up := time.Duration(uptimeFloat) * time.Second
+
+	b := modules.Block{
+		FullText:            fmt.Sprintf("%s %s", fontawesome.ArrowCircleUp, human.Duration(up)),
+		Name:                "uptime",
+		Instance:            m.instance,
+		Separator:           true,
+		SeparatorBlockWidth: 20,
+	}
+
+	return json.Marshal(b)
+}
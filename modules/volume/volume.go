@@ -0,0 +1,161 @@
+// Package volume implements the modules.Module that reports an ALSA
+// mixer's volume via amixer.
+package volume
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/metalmatze/i3bargo/fontawesome"
+	"github.com/metalmatze/i3bargo/modules"
+)
+
+// Module reports the volume of Mixer, refreshing as soon as
+// alsactl monitor observes a change and falling back to Interval as a
+// backstop for events it misses.
+type Module struct {
+	Interval time.Duration
+	// Mixer is the amixer control name to query, e.g. "Master".
+	Mixer string
+
+	instance string
+}
+
+// New builds a Module, defaulting Interval to 30 seconds (alsactl monitor
+// covers the common case; this is just a backstop) and Mixer to Master.
+// instance distinguishes this Module from any other "volume" instance in
+// the config (e.g. a second mixer) and is used to place Updates and route
+// click events.
+func New(interval time.Duration, mixer, instance string) *Module {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	if mixer == "" {
+		mixer = "Master"
+	}
+	return &Module{Interval: interval, Mixer: mixer, instance: instance}
+}
+
+// Name implements modules.Module.
+func (m *Module) Name() string { return "volume" }
+
+// Instance implements modules.Module.
+func (m *Module) Instance() string { return m.instance }
+
+// Run implements modules.Module.
+func (m *Module) Run(ctx context.Context, wake <-chan struct{}, out chan<- modules.Update) error {
+	changes, err := m.monitor(ctx)
+	if err != nil {
+		changes = nil // fall back to polling on Interval alone
+	}
+
+	read := func() (json.RawMessage, error) { return m.read(ctx) }
+	return modules.RunLoop(ctx, wake, out, m.Name(), m.Instance(), m.Interval, changes, read)
+}
+
+// monitor runs `alsactl monitor` and returns a channel that receives a
+// value every time it reports a control change, i.e. whenever the volume
+// or mute state changes. The channel is closed once alsactl exits.
+func (m *Module) monitor(ctx context.Context) (<-chan struct{}, error) {
+	cmd := exec.CommandContext(ctx, "alsactl", "monitor")
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("alsactl monitor: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("alsactl monitor: %w", err)
+	}
+
+	changes := make(chan struct{})
+	go func() {
+		defer close(changes)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			select {
+			case changes <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return changes, nil
+}
+
+// HandleClick implements modules.ClickHandler: button 1 toggles mute and
+// the scroll wheel (buttons 4/5) nudges the volume up/down by 5%.
+func (m *Module) HandleClick(event modules.ClickEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+
+	var arg string
+	switch event.Button {
+	case 1:
+		arg = "toggle"
+	case 4:
+		arg = "5%+"
+	case 5:
+		arg = "5%-"
+	default:
+		return nil
+	}
+
+	if err := exec.CommandContext(ctx, "amixer", "-D", "default", "set", m.Mixer, arg).Run(); err != nil {
+		return fmt.Errorf("amixer set: %w", err)
+	}
+	return nil
+}
+
+var volumeRegex = regexp.MustCompile(`\[(\d{1,3})\%\]\s\[(on|off)\]`)
+
+func (m *Module) read(ctx context.Context) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 250*time.Millisecond)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "amixer", "-D", "default", "get", m.Mixer)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("amixer get: %w", err)
+	}
+
+	var volText, muteText string
+
+	scanner := bufio.NewScanner(bytes.NewBuffer(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if volumeRegex.MatchString(line) {
+			matches := volumeRegex.FindStringSubmatch(line)
+			volText, muteText = matches[1], matches[2]
+			break
+		}
+	}
+
+	vol, err := strconv.ParseInt(volText, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse volume: %w", err)
+	}
+
+	fulltext := fmt.Sprintf("%d%%", vol)
+	if muteText == "off" {
+		fulltext = "off"
+	}
+
+	b := modules.Block{
+		FullText:            fmt.Sprintf("%s %s", fontawesome.VolumeUp, fulltext),
+		Name:                "volume",
+		Instance:            m.instance,
+		Separator:           true,
+		SeparatorBlockWidth: 20,
+	}
+
+	return json.Marshal(b)
+}
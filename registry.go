@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/metalmatze/i3bargo/modules"
+	"github.com/metalmatze/i3bargo/modules/battery"
+	"github.com/metalmatze/i3bargo/modules/datetime"
+	"github.com/metalmatze/i3bargo/modules/memory"
+	"github.com/metalmatze/i3bargo/modules/network"
+	"github.com/metalmatze/i3bargo/modules/temperature"
+	"github.com/metalmatze/i3bargo/modules/uptime"
+	"github.com/metalmatze/i3bargo/modules/volume"
+)
+
+// newModule builds the Module described by cfg. instance distinguishes it
+// from any other module of the same Type in the config; see ModuleConfig.Instance.
+func newModule(cfg ModuleConfig, instance string) (modules.Module, error) {
+	interval, err := cfg.interval()
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Type {
+	case "memory":
+		formatter, err := cfg.formatter()
+		if err != nil {
+			return nil, err
+		}
+		return memory.New(interval, formatter, instance), nil
+	case "volume":
+		return volume.New(interval, cfg.Options["mixer"], instance), nil
+	case "temperature":
+		return temperature.New(interval, cfg.Options["path"], instance), nil
+	case "battery":
+		return battery.New(interval, 0, instance), nil
+	case "uptime":
+		return uptime.New(interval, instance), nil
+	case "datetime":
+		return datetime.New(interval, cfg.Options["format"], instance), nil
+	case "network":
+		formatter, err := cfg.formatter()
+		if err != nil {
+			return nil, err
+		}
+		return network.New(interval, cfg.Options["iface"], formatter, instance), nil
+	default:
+		return nil, fmt.Errorf("unknown module type %q", cfg.Type)
+	}
+}